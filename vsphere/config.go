@@ -0,0 +1,87 @@
+package vsphere
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
+	"github.com/vmware/govmomi"
+)
+
+// vimConnection holds the connection established against a single vCenter
+// endpoint. It carries only the core SOAP API client; no REST-based client
+// (such as a tags client) is kept per endpoint. It is keyed by alias inside
+// VSphereClient.
+type vimConnection struct {
+	alias     string
+	vimClient *govmomi.Client
+}
+
+// VSphereClient holds a keyed collection of connections to one or more
+// vCenter endpoints, one per `vcenter` block declared in the provider
+// configuration. Resources that are not vCenter-aware (or that do not set
+// an explicit `vcenter` attribute) use the connection for defaultAlias,
+// which preserves the historical single-vCenter behavior of this provider.
+type VSphereClient struct {
+	// connections is the set of established connections, keyed by the
+	// alias assigned in the corresponding `vcenter` provider block.
+	connections map[string]*vimConnection
+
+	// defaultAlias is the alias of the connection used when a resource does
+	// not specify a `vcenter` attribute. It is the alias of the first
+	// `vcenter` block in the provider configuration.
+	defaultAlias string
+
+	// vimClient is the connection for defaultAlias. It is kept alongside
+	// connections so that resources that have not been made vCenter-aware
+	// can keep referencing meta.(*VSphereClient).vimClient unchanged.
+	vimClient *govmomi.Client
+}
+
+// clientForAlias returns the connection registered under alias. An empty
+// alias resolves to the default connection, preserving the behavior
+// resources saw before the introduction of multi-vCenter support.
+func (c *VSphereClient) clientForAlias(alias string) (*govmomi.Client, error) {
+	if alias == "" {
+		alias = c.defaultAlias
+	}
+	conn, ok := c.connections[alias]
+	if !ok {
+		return nil, fmt.Errorf("no vcenter block configured with alias %q", alias)
+	}
+	return conn.vimClient, nil
+}
+
+// clientForCluster walks every configured vCenter connection looking for one
+// that can resolve clusterID to a cluster. It lets a resource locate a
+// cluster-scoped object without the user specifying which vCenter it lives
+// in, at the cost of an extra property collector round trip per additional
+// configured endpoint.
+//
+// Cluster managed object IDs (e.g. "domain-c7") are assigned per-vCenter and
+// are not guaranteed to be unique across endpoints, so clusterID matching
+// more than one configured connection is treated as an error rather than
+// resolved by picking one arbitrarily (connections is a map, so "the first
+// match" would be nondeterministic besides being wrong). Callers that expect
+// to see this should set the resource's `vcenter` attribute to disambiguate.
+func (c *VSphereClient) clientForCluster(clusterID string) (string, *govmomi.Client, error) {
+	var matchAlias string
+	var matchClient *govmomi.Client
+	var matchedAliases []string
+
+	for alias, conn := range c.connections {
+		if _, err := clustercomputeresource.FromID(conn.vimClient, clusterID); err == nil {
+			matchAlias, matchClient = alias, conn.vimClient
+			matchedAliases = append(matchedAliases, alias)
+		}
+	}
+
+	switch len(matchedAliases) {
+	case 0:
+		return "", nil, fmt.Errorf("cluster ID %q was not found in any configured vcenter block", clusterID)
+	case 1:
+		return matchAlias, matchClient, nil
+	default:
+		return "", nil, fmt.Errorf("cluster ID %q was found in more than one configured vcenter block (%s); set the vcenter attribute to disambiguate", clusterID, strings.Join(matchedAliases, ", "))
+	}
+}
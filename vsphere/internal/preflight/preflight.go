@@ -0,0 +1,192 @@
+// Package preflight holds checks that resources run against a cluster and/or
+// virtual machine before submitting a Reconfigure call that depends on
+// cluster-wide features (HA, DRS) being enabled, or on the connected user
+// holding specific privileges. Each check returns a Diagnostic describing
+// what it found, rather than an error, so that a resource can decide whether
+// a given condition is fatal or merely worth warning about.
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Severity describes how serious a Diagnostic is.
+type Severity string
+
+const (
+	// SeverityError indicates a condition that must be resolved before the
+	// requested change can be applied.
+	SeverityError Severity = "error"
+
+	// SeverityWarning indicates a condition that the caller should be made
+	// aware of, but that does not by itself prevent the requested change
+	// from being applied.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single finding produced by a check in this package.
+type Diagnostic struct {
+	// Severity indicates whether this Diagnostic should be treated as fatal.
+	Severity Severity
+
+	// Summary is a short, one-line description of the condition found.
+	Summary string
+
+	// Remediation describes what the caller can do to resolve the
+	// condition, when applicable.
+	Remediation string
+}
+
+// HasErrors returns true if diags contains at least one Diagnostic at
+// SeverityError.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterHasHAEnabled checks that cluster has vSphere HA enabled. It should
+// be run whenever the caller is about to submit DasVmConfigSpec entries.
+func ClusterHasHAEnabled(cluster *object.ClusterComputeResource) (Diagnostic, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return Diagnostic{}, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+
+	config, ok := props.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok || config.DasConfig.Enabled == nil || !*config.DasConfig.Enabled {
+		return Diagnostic{
+			Severity:    SeverityError,
+			Summary:     fmt.Sprintf("cluster %q does not have vSphere HA enabled", cluster.Name()),
+			Remediation: "enable HA on the cluster, or remove the HA-specific attributes from this configuration",
+		}, nil
+	}
+	return Diagnostic{}, nil
+}
+
+// ClusterHasDRSEnabled checks that cluster has DRS enabled. It should be run
+// whenever the caller is about to submit DrsVmConfigSpec entries.
+func ClusterHasDRSEnabled(cluster *object.ClusterComputeResource) (Diagnostic, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return Diagnostic{}, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+
+	config, ok := props.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok || config.DrsConfig.Enabled == nil || !*config.DrsConfig.Enabled {
+		return Diagnostic{
+			Severity:    SeverityError,
+			Summary:     fmt.Sprintf("cluster %q does not have DRS enabled", cluster.Name()),
+			Remediation: "enable DRS on the cluster, or remove the DRS-specific attributes from this configuration",
+		}, nil
+	}
+	return Diagnostic{}, nil
+}
+
+// VMIsClusterMember checks that vm is currently a member of the resource
+// pool hierarchy rooted at cluster.
+func VMIsClusterMember(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (Diagnostic, error) {
+	props, err := virtualmachine.Properties(vm)
+	if err != nil {
+		return Diagnostic{}, fmt.Errorf("error fetching virtual machine properties: %s", err)
+	}
+	if props.ResourcePool == nil {
+		return Diagnostic{
+			Severity:    SeverityError,
+			Summary:     fmt.Sprintf("virtual machine %q has no resource pool and cannot be a member of cluster %q", vm.Name(), cluster.Name()),
+			Remediation: "move the virtual machine into the target cluster before applying this override",
+		}, nil
+	}
+
+	clusterProps, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return Diagnostic{}, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+	if clusterProps.ResourcePool == nil {
+		return Diagnostic{}, fmt.Errorf("cluster %q has no root resource pool", cluster.Name())
+	}
+
+	member, err := resourcePoolIsDescendant(vm, *clusterProps.ResourcePool, *props.ResourcePool)
+	if err != nil {
+		return Diagnostic{}, err
+	}
+	if !member {
+		return Diagnostic{
+			Severity:    SeverityError,
+			Summary:     fmt.Sprintf("virtual machine %q is not a member of cluster %q", vm.Name(), cluster.Name()),
+			Remediation: "move the virtual machine into the target cluster before applying this override",
+		}, nil
+	}
+
+	return Diagnostic{}, nil
+}
+
+// resourcePoolIsDescendant walks the parent chain of candidate, returning
+// true if root is found anywhere in it (including candidate itself being
+// root).
+func resourcePoolIsDescendant(vm *object.VirtualMachine, root types.ManagedObjectReference, candidate types.ManagedObjectReference) (bool, error) {
+	ctx := context.Background()
+	ref := candidate
+	for {
+		if ref == root {
+			return true, nil
+		}
+		if ref.Type != "ResourcePool" {
+			return false, nil
+		}
+		pool := object.NewResourcePool(vm.Client(), ref)
+		var o mo.ResourcePool
+		if err := pool.Properties(ctx, ref, []string{"parent"}, &o); err != nil {
+			return false, fmt.Errorf("error walking resource pool hierarchy: %s", err)
+		}
+		if o.Parent == nil {
+			return false, nil
+		}
+		ref = *o.Parent
+	}
+}
+
+// UserHasEditClusterPrivilege checks that the connected user holds the
+// Host.Inventory.EditCluster privilege on cluster.
+func UserHasEditClusterPrivilege(cluster *object.ClusterComputeResource) (Diagnostic, error) {
+	const privilege = "Host.Inventory.EditCluster"
+
+	ok, err := viapi.HasPrivilegeOnEntity(cluster.Client(), cluster.Reference(), privilege)
+	if err != nil {
+		return Diagnostic{}, fmt.Errorf("error checking privileges on cluster %q: %s", cluster.Name(), err)
+	}
+	if !ok {
+		return Diagnostic{
+			Severity:    SeverityError,
+			Summary:     fmt.Sprintf("connected user is missing the %s privilege on cluster %q", privilege, cluster.Name()),
+			Remediation: "grant the connected user the Host.Inventory.EditCluster privilege on the cluster, or a role that includes it",
+		}, nil
+	}
+	return Diagnostic{}, nil
+}
+
+// APDPDLFieldsSupported checks that cluster's vCenter is running a version
+// that supports the APD/PDL response fields in ClusterVmComponentProtectionSettings,
+// which were introduced in vSphere 6.0.
+func APDPDLFieldsSupported(cluster *object.ClusterComputeResource) (Diagnostic, error) {
+	about := cluster.Client().ServiceContent.About
+	if !viapi.VersionAtLeast(about, 6, 0) {
+		return Diagnostic{
+			Severity:    SeverityWarning,
+			Summary:     fmt.Sprintf("vCenter %s (version %s) predates APD/PDL response support, which requires vSphere 6.0 or later", about.InstanceUuid, about.Version),
+			Remediation: "upgrade vCenter, or remove the ha_datastore_apd_response, ha_datastore_apd_recovery_action, and ha_datastore_pdl_response attributes",
+		}, nil
+	}
+	return Diagnostic{}, nil
+}
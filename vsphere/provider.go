@@ -0,0 +1,141 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// Provider returns the actual provider instance.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"vcenter": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "One or more vCenter endpoints that this provider can address. Resources that support the `vcenter` attribute select which endpoint in this list to operate against by alias.",
+				Elem:        vcenterEndpointSchema(),
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"vsphere_compute_cluster_failure_domain": resourceVSphereComputeClusterFailureDomain(),
+			"vsphere_ha_vm_override":                 resourceVSphereHaVMOverride(),
+			"vsphere_ha_vm_override_set":             resourceVSphereHaVMOverrideSet(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+// vcenterEndpointSchema is the schema for a single `vcenter` block in the
+// provider configuration.
+func vcenterEndpointSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"alias": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A stable name for this vCenter endpoint, used by the `vcenter` attribute on resources to select which connection they should use. Defaults to the endpoint's server address.",
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The vCenter server name for this endpoint.",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The user name to authenticate to this vCenter endpoint as.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password to authenticate to this vCenter endpoint with.",
+			},
+			"datacenters": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Restricts this endpoint to the given list of datacenters. If unset, all datacenters visible to the user on this endpoint are in scope.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	blocks := d.Get("vcenter").([]interface{})
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("at least one vcenter block must be configured")
+	}
+
+	client := &VSphereClient{
+		connections: make(map[string]*vimConnection, len(blocks)),
+	}
+
+	for _, raw := range blocks {
+		block := raw.(map[string]interface{})
+		server := block["server"].(string)
+		alias := block["alias"].(string)
+		if alias == "" {
+			alias = server
+		}
+		if _, ok := client.connections[alias]; ok {
+			return nil, fmt.Errorf("duplicate vcenter alias %q", alias)
+		}
+
+		vimClient, err := dialVimClient(server, block["user"].(string), block["password"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to vcenter %q: %s", alias, err)
+		}
+		if err := viapi.ValidateVirtualCenter(vimClient); err != nil {
+			return nil, err
+		}
+
+		client.connections[alias] = &vimConnection{alias: alias, vimClient: vimClient}
+		if client.defaultAlias == "" {
+			client.defaultAlias = alias
+			client.vimClient = vimClient
+		}
+	}
+
+	return client, nil
+}
+
+// dialVimClient establishes a single govmomi connection to server,
+// authenticating with user/password.
+func dialVimClient(server, user, password string) (*govmomi.Client, error) {
+	u, err := soap.ParseURL(server)
+	if err != nil {
+		return nil, err
+	}
+	u.User = url.UserPassword(user, password)
+
+	ctx := context.Background()
+	client, err := govmomi.NewClient(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Login(ctx, u.User); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// mergeSchema merges the map in src into dst, overwriting any pre-existing
+// keys. It's used to build a resource's schema out of field sets shared with
+// other resources (for example, the DRS/HA override fields shared by
+// vsphere_ha_vm_override and vsphere_ha_vm_override_set).
+func mergeSchema(dst, src map[string]*schema.Schema) map[string]*schema.Schema {
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
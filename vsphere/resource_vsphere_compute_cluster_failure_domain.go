@@ -0,0 +1,264 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const resourceVSphereComputeClusterFailureDomainName = "vsphere_compute_cluster_failure_domain"
+
+// resourceVSphereComputeClusterFailureDomain manages a named failure domain
+// within a cluster: a group of hosts (typically a rack or an availability
+// zone) that vsphere_ha_vm_override's failure_domain_id attribute can target
+// with a VM-Host affinity rule. The host group is the only part of a failure
+// domain that vSphere itself understands; datastore_id and network_id are
+// recorded for the benefit of other resources that want to co-locate storage
+// or network placement with the same failure domain, but are not otherwise
+// enforced by this resource.
+func resourceVSphereComputeClusterFailureDomain() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterFailureDomainCreate,
+		Read:   resourceVSphereComputeClusterFailureDomainRead,
+		Update: resourceVSphereComputeClusterFailureDomainUpdate,
+		Delete: resourceVSphereComputeClusterFailureDomainDelete,
+
+		Schema: map[string]*schema.Schema{
+			"compute_cluster_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The managed object ID of the cluster.",
+			},
+			"vcenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The alias of the vcenter provider block to use for this resource's cluster, as set in that block's alias attribute. If unset, the provider locates compute_cluster_id by walking every configured vcenter block.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of this failure domain. Used as the name of the underlying host group, so it must be unique within the cluster.",
+			},
+			"host_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The managed object IDs of the hosts that make up this failure domain.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"datastore_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The managed object ID of the datastore backing this failure domain, if any. Not enforced by vSphere; recorded for use by other resources.",
+			},
+			"network_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The managed object ID of the network backing this failure domain, if any. Not enforced by vSphere; recorded for use by other resources.",
+			},
+		},
+	}
+}
+
+func resourceVSphereComputeClusterFailureDomainCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterFailureDomainIDString(d))
+
+	alias, cluster, err := resourceVSphereComputeClusterFailureDomainCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationAdd,
+				},
+				Info: expandClusterHostGroup(d),
+			},
+		},
+	}
+
+	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
+		return err
+	}
+
+	d.SetId(strings.Join([]string{alias, cluster.Reference().Value, d.Get("name").(string)}, ":"))
+
+	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterFailureDomainIDString(d))
+	return resourceVSphereComputeClusterFailureDomainRead(d, meta)
+}
+
+func resourceVSphereComputeClusterFailureDomainRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterFailureDomainIDString(d))
+
+	_, cluster, err := resourceVSphereComputeClusterFailureDomainCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group, err := resourceVSphereComputeClusterFailureDomainFindGroup(cluster, d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
+		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+	}
+
+	hostIDs := make([]string, 0, len(group.Host))
+	for _, host := range group.Host {
+		hostIDs = append(hostIDs, host.Value)
+	}
+	if err := d.Set("host_ids", hostIDs); err != nil {
+		return fmt.Errorf("error setting attribute \"host_ids\": %s", err)
+	}
+
+	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterFailureDomainIDString(d))
+	return nil
+}
+
+func resourceVSphereComputeClusterFailureDomainUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterFailureDomainIDString(d))
+
+	_, cluster, err := resourceVSphereComputeClusterFailureDomainCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					// Unlike the DRS/DAS VmConfig arrays elsewhere in this
+					// provider, host groups are keyed by name: Add faults if a
+					// group with this name already exists, so Edit must be used
+					// to change the membership of a group created by a prior
+					// apply.
+					Operation: types.ArrayUpdateOperationEdit,
+				},
+				Info: expandClusterHostGroup(d),
+			},
+		},
+	}
+
+	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterFailureDomainIDString(d))
+	return resourceVSphereComputeClusterFailureDomainRead(d, meta)
+}
+
+func resourceVSphereComputeClusterFailureDomainDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterFailureDomainIDString(d))
+
+	_, cluster, err := resourceVSphereComputeClusterFailureDomainCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationRemove,
+					RemoveKey: d.Get("name").(string),
+				},
+			},
+		},
+	}
+
+	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterFailureDomainIDString(d))
+	return nil
+}
+
+// resourceVSphereComputeClusterFailureDomainIDString prints a friendly
+// string for the vsphere_compute_cluster_failure_domain resource.
+func resourceVSphereComputeClusterFailureDomainIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereComputeClusterFailureDomainName)
+}
+
+// resourceVSphereComputeClusterFailureDomainParseID parses an ID for the
+// vsphere_compute_cluster_failure_domain resource and outputs its parts: the
+// vcenter alias, the cluster's managed object ID, and the host group name.
+func resourceVSphereComputeClusterFailureDomainParseID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) < 3 {
+		return "", "", "", fmt.Errorf("bad ID %q", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// resourceVSphereComputeClusterFailureDomainCluster resolves the vcenter
+// alias and cluster that this resource's compute_cluster_id/vcenter
+// attributes target.
+func resourceVSphereComputeClusterFailureDomainCluster(d *schema.ResourceData, meta interface{}) (string, *object.ClusterComputeResource, error) {
+	clusterID := d.Get("compute_cluster_id").(string)
+	client, alias, err := resourceVSphereHaVMOverrideClientForCluster(meta, d.Get("vcenter").(string), clusterID)
+	if err != nil {
+		return "", nil, err
+	}
+	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot locate cluster: %s", err)
+	}
+	return alias, cluster, nil
+}
+
+// expandClusterHostGroup reads the name and host_ids attributes into a
+// ClusterHostGroup.
+func expandClusterHostGroup(d *schema.ResourceData) *types.ClusterHostGroup {
+	raw := d.Get("host_ids").(*schema.Set).List()
+	hosts := make([]types.ManagedObjectReference, 0, len(raw))
+	for _, v := range raw {
+		hosts = append(hosts, types.ManagedObjectReference{Type: "HostSystem", Value: v.(string)})
+	}
+
+	return &types.ClusterHostGroup{
+		ClusterGroupInfo: types.ClusterGroupInfo{
+			Name: d.Get("name").(string),
+		},
+		Host: hosts,
+	}
+}
+
+// resourceVSphereComputeClusterFailureDomainFindGroup locates the
+// ClusterHostGroup named name in cluster's configuration. nil is returned if
+// no such group exists, or if a group with that name exists but is not a
+// host group.
+func resourceVSphereComputeClusterFailureDomainFindGroup(cluster *object.ClusterComputeResource, name string) (*types.ClusterHostGroup, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+
+	for _, g := range props.ConfigurationEx.(*types.ClusterConfigInfoEx).Group {
+		hostGroup, ok := g.(*types.ClusterHostGroup)
+		if !ok {
+			continue
+		}
+		if hostGroup.Name == name {
+			return hostGroup, nil
+		}
+	}
+
+	return nil, nil
+}
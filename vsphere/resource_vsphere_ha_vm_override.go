@@ -13,8 +13,10 @@ import (
 	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/preflight"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -30,7 +32,7 @@ func resourceVSphereHaVMOverride() *schema.Resource {
 			State: resourceVSphereHaVMOverrideImport,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: mergeSchema(map[string]*schema.Schema{
 			"compute_cluster_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -43,32 +45,183 @@ func resourceVSphereHaVMOverride() *schema.Resource {
 				ForceNew:    true,
 				Description: "The managed object ID of the virtual machine.",
 			},
-			"drs_enabled": {
-				Type:        schema.TypeBool,
+			"vcenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The alias of the vcenter provider block to use for this resource's cluster and virtual machine, as set in that block's alias attribute. If unset, the provider locates compute_cluster_id by walking every configured vcenter block.",
+			},
+			"failure_domain_id": {
+				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     false,
-				Description: "Enable DRS for this virtual machine.",
+				Description: "The ID of a vsphere_compute_cluster_failure_domain resource. When set, the virtual machine is placed into a VM group that is tied with a should-run-on affinity rule to the failure domain's host group.",
 			},
-			"drs_automation_level": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      string(types.DrsBehaviorManual),
-				Description:  "The automation level for this virtual machine in the cluster. Can be one of manual, partiallyAutomated, or fullyAutomated.",
-				ValidateFunc: validation.StringInSlice(drsBehaviorAllowedValues, false),
+			"extra_config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Extra advanced configuration parameters to set on the virtual machine, such as guestinfo.* or tools.* keys. Applied directly to the virtual machine via VirtualMachine.Reconfigure, independently of the cluster-level HA/DRS overrides managed by this resource.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+		}, haVMOverrideFieldSchema()),
+	}
+}
+
+// haVMOverrideFieldSchema returns the set of DRS+HA override attributes
+// shared by vsphere_ha_vm_override and vsphere_ha_vm_override_set.
+func haVMOverrideFieldSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"skip_preflight": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Skip the preflight validation checks (HA/DRS enablement, cluster membership, privileges, and version support) that normally run before this resource is applied. Intended for CI environments where those conditions are already known to be satisfied.",
+		},
+		"drs_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Enable DRS for this virtual machine.",
+		},
+		"drs_automation_level": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.DrsBehaviorManual),
+			Description:  "The automation level for this virtual machine in the cluster. Can be one of manual, partiallyAutomated, or fullyAutomated.",
+			ValidateFunc: validation.StringInSlice(drsBehaviorAllowedValues, false),
+		},
+		"ha_vm_restart_priority": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterDasVmSettingsRestartPriorityMedium),
+			Description:  "The restart priority for this virtual machine when vSphere HA restarts it after a host failure. Can be one of disabled, lowest, low, medium, high, or highest.",
+			ValidateFunc: validation.StringInSlice(haVMRestartPriorityAllowedValues, false),
+		},
+		"ha_vm_restart_priority_timeout": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "The maximum time, in seconds, that vSphere HA waits for the virtual machine to be ready before moving on to the next priority group in the restart sequence. Only applies when ha_vm_restart_priority is not disabled.",
+		},
+		"ha_host_isolation_response": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterDasVmSettingsIsolationResponseNone),
+			Description:  "The action to take on this virtual machine if a host determines that it is isolated from the rest of the cluster. Can be one of none, powerOff, or shutdown.",
+			ValidateFunc: validation.StringInSlice(haHostIsolationResponseAllowedValues, false),
+		},
+		"ha_vm_monitoring": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterDasConfigInfoVmMonitoringStateVmMonitoringDisabled),
+			Description:  "The type of virtual machine monitoring to use for this virtual machine. Can be one of vmMonitoringDisabled, vmMonitoringOnly, or vmAndAppMonitoring.",
+			ValidateFunc: validation.StringInSlice(haVMMonitoringAllowedValues, false),
+		},
+		"ha_vm_failure_interval": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     30,
+			Description: "The number of seconds after which the virtual machine is considered failed if no heartbeat has been received, when ha_vm_monitoring is enabled.",
+		},
+		"ha_vm_minimum_uptime": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     120,
+			Description: "The time, in seconds, that vSphere HA waits after powering on the virtual machine before starting to count failures for ha_vm_monitoring.",
+		},
+		"ha_vm_maximum_failures": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     3,
+			Description: "The maximum number of failures and automated resets allowed for the virtual machine within ha_vm_maximum_failure_window, when ha_vm_monitoring is enabled.",
+		},
+		"ha_vm_maximum_failure_window": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     -1,
+			Description: "The length of the reset period, in seconds, in which ha_vm_maximum_failures applies. -1 means no window, so all failures are counted.",
+		},
+		"ha_datastore_apd_response": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDClusterDefault),
+			Description:  "The response that vSphere HA makes to this virtual machine when the cluster has detected an all paths down (APD) condition in the storage subsystem.",
+			ValidateFunc: validation.StringInSlice(haDatastoreAPDResponseAllowedValues, false),
+		},
+		"ha_datastore_apd_recovery_action": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterVmComponentProtectionSettingsVmReactionOnAPDClearedNone),
+			Description:  "The action to take on this virtual machine if an APD status on an affected datastore clears in the middle of the APD timeout. Can be one of none or reset.",
+			ValidateFunc: validation.StringInSlice(haDatastoreAPDRecoveryActionAllowedValues, false),
+		},
+		"ha_datastore_pdl_response": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForPDLClusterDefault),
+			Description:  "The response that vSphere HA makes to this virtual machine when the cluster has detected a permanent device loss (PDL) condition in the storage subsystem.",
+			ValidateFunc: validation.StringInSlice(haDatastorePDLResponseAllowedValues, false),
 		},
 	}
 }
 
+var haVMRestartPriorityAllowedValues = []string{
+	string(types.ClusterDasVmSettingsRestartPriorityDisabled),
+	string(types.ClusterDasVmSettingsRestartPriorityLowest),
+	string(types.ClusterDasVmSettingsRestartPriorityLow),
+	string(types.ClusterDasVmSettingsRestartPriorityMedium),
+	string(types.ClusterDasVmSettingsRestartPriorityHigh),
+	string(types.ClusterDasVmSettingsRestartPriorityHighest),
+}
+
+var haHostIsolationResponseAllowedValues = []string{
+	string(types.ClusterDasVmSettingsIsolationResponseNone),
+	string(types.ClusterDasVmSettingsIsolationResponsePowerOff),
+	string(types.ClusterDasVmSettingsIsolationResponseShutdown),
+}
+
+var haVMMonitoringAllowedValues = []string{
+	string(types.ClusterDasConfigInfoVmMonitoringStateVmMonitoringDisabled),
+	string(types.ClusterDasConfigInfoVmMonitoringStateVmMonitoringOnly),
+	string(types.ClusterDasConfigInfoVmMonitoringStateVmAndAppMonitoring),
+}
+
+var haDatastoreAPDResponseAllowedValues = []string{
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDDisabled),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDWarning),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDRestartConservative),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDRestartAggressive),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForAPDClusterDefault),
+}
+
+var haDatastoreAPDRecoveryActionAllowedValues = []string{
+	string(types.ClusterVmComponentProtectionSettingsVmReactionOnAPDClearedNone),
+	string(types.ClusterVmComponentProtectionSettingsVmReactionOnAPDClearedReset),
+}
+
+var haDatastorePDLResponseAllowedValues = []string{
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForPDLDisabled),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForPDLWarning),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForPDLRestartAggressive),
+	string(types.ClusterVmComponentProtectionSettingsVmStorageProtectionForPDLClusterDefault),
+}
+
 func resourceVSphereHaVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereHaVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
+	alias, cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
 	if err != nil {
 		return err
 	}
 
-	info, err := expandClusterDrsVMConfigInfo(d, vm)
+	if err := resourceVSphereHaVMOverridePreflight(d, cluster, vm); err != nil {
+		return err
+	}
+
+	drsInfo, err := expandClusterDrsVMConfigInfo(d, vm)
+	if err != nil {
+		return err
+	}
+	dasInfo, err := expandClusterDasVMConfigInfo(d, vm)
 	if err != nil {
 		return err
 	}
@@ -78,16 +231,37 @@ func resourceVSphereHaVMOverrideCreate(d *schema.ResourceData, meta interface{})
 				ArrayUpdateSpec: types.ArrayUpdateSpec{
 					Operation: types.ArrayUpdateOperationAdd,
 				},
-				Info: info,
+				Info: drsInfo,
 			},
 		},
+		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationAdd,
+				},
+				Info: *dasInfo,
+			},
+		},
+	}
+
+	groupSpec, ruleSpec, ok, err := expandFailureDomainPlacement(d, cluster, vm)
+	if err != nil {
+		return err
+	}
+	if ok {
+		spec.GroupSpec = append(spec.GroupSpec, *groupSpec)
+		spec.RulesSpec = append(spec.RulesSpec, *ruleSpec)
 	}
 
 	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
 		return err
 	}
 
-	id, err := resourceVSphereHaVMOverrideFlattenID(cluster, vm)
+	if err := resourceVSphereHaVMOverrideApplyExtraConfig(d, vm); err != nil {
+		return err
+	}
+
+	id, err := resourceVSphereHaVMOverrideFlattenID(alias, cluster, vm)
 	if err != nil {
 		return fmt.Errorf("cannot compute ID of created resource: %s", err)
 	}
@@ -100,18 +274,24 @@ func resourceVSphereHaVMOverrideCreate(d *schema.ResourceData, meta interface{})
 func resourceVSphereHaVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereHaVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
+	_, cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
+	if err != nil {
+		return err
+	}
+
+	drsInfo, err := resourceVSphereHaVMOverrideFindEntry(cluster, vm)
 	if err != nil {
 		return err
 	}
 
-	info, err := resourceVSphereHaVMOverrideFindEntry(cluster, vm)
+	dasInfo, err := resourceVSphereHaVMOverrideFindDasEntry(cluster, vm)
 	if err != nil {
 		return err
 	}
 
-	if info == nil {
-		// The configuration is missing, blank out the ID so it can be re-created.
+	if drsInfo == nil && dasInfo == nil {
+		// Neither the DRS nor the DAS configuration is present, blank out the ID
+		// so it can be re-created.
 		d.SetId("")
 		return nil
 	}
@@ -131,10 +311,38 @@ func resourceVSphereHaVMOverrideRead(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
 	}
 
-	if err = flattenClusterDrsVMConfigInfo(d, info); err != nil {
+	if drsInfo != nil {
+		if err = flattenClusterDrsVMConfigInfo(d, drsInfo); err != nil {
+			return err
+		}
+	}
+
+	if err := resourceVSphereHaVMOverrideFlattenExtraConfig(d, vm, props); err != nil {
 		return err
 	}
 
+	if dasInfo != nil {
+		if err = flattenClusterDasVMConfigInfo(d, dasInfo); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("failure_domain_id").(string) != "" {
+		exists, err := resourceVSphereHaVMOverrideFailureDomainPlacementExists(cluster, props.Config.Uuid)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// The VM group and/or should-run-on rule that failure_domain_id
+			// provisioned was removed out of band. Blank the attribute so
+			// Terraform reports drift and the next apply re-creates them.
+			log.Printf("[DEBUG] %s: failure domain VM group/rule no longer present, clearing failure_domain_id", resourceVSphereHaVMOverrideIDString(d))
+			if err := d.Set("failure_domain_id", ""); err != nil {
+				return fmt.Errorf("error setting attribute \"failure_domain_id\": %s", err)
+			}
+		}
+	}
+
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereHaVMOverrideIDString(d))
 	return nil
 }
@@ -142,12 +350,20 @@ func resourceVSphereHaVMOverrideRead(d *schema.ResourceData, meta interface{}) e
 func resourceVSphereHaVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereHaVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
+	_, cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
 	if err != nil {
 		return err
 	}
 
-	info, err := expandClusterDrsVMConfigInfo(d, vm)
+	if err := resourceVSphereHaVMOverridePreflight(d, cluster, vm); err != nil {
+		return err
+	}
+
+	drsInfo, err := expandClusterDrsVMConfigInfo(d, vm)
+	if err != nil {
+		return err
+	}
+	dasInfo, err := expandClusterDasVMConfigInfo(d, vm)
 	if err != nil {
 		return err
 	}
@@ -160,15 +376,58 @@ func resourceVSphereHaVMOverrideUpdate(d *schema.ResourceData, meta interface{})
 					// that have missing fields.
 					Operation: types.ArrayUpdateOperationAdd,
 				},
-				Info: info,
+				Info: drsInfo,
+			},
+		},
+		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					// Same semantics as above: Add replaces the existing entry wholesale.
+					Operation: types.ArrayUpdateOperationAdd,
+				},
+				Info: *dasInfo,
 			},
 		},
 	}
 
+	groupSpec, ruleSpec, ok, err := expandFailureDomainPlacement(d, cluster, vm)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ok:
+		// Unlike the DRS/DAS VmConfig arrays above, expandFailureDomainPlacement
+		// already chose Add vs. Edit for the VM group/rule depending on whether
+		// they exist yet, since groups are keyed by name and rules by an
+		// integer Key rather than replaced wholesale by Add.
+		spec.GroupSpec = append(spec.GroupSpec, *groupSpec)
+		spec.RulesSpec = append(spec.RulesSpec, *ruleSpec)
+	default:
+		// failure_domain_id was cleared. Clean up any VM group/rule that a
+		// previous apply created, leaving the shared host group alone.
+		old, _ := d.GetChange("failure_domain_id")
+		if old.(string) != "" {
+			removeGroupSpec, removeRuleSpec, err := resourceVSphereHaVMOverrideFailureDomainRemoveSpecs(cluster, vm)
+			if err != nil {
+				return err
+			}
+			if removeGroupSpec != nil {
+				spec.GroupSpec = append(spec.GroupSpec, *removeGroupSpec)
+			}
+			if removeRuleSpec != nil {
+				spec.RulesSpec = append(spec.RulesSpec, *removeRuleSpec)
+			}
+		}
+	}
+
 	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
 		return err
 	}
 
+	if err := resourceVSphereHaVMOverrideApplyExtraConfig(d, vm); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereHaVMOverrideIDString(d))
 	return resourceVSphereHaVMOverrideRead(d, meta)
 }
@@ -176,7 +435,7 @@ func resourceVSphereHaVMOverrideUpdate(d *schema.ResourceData, meta interface{})
 func resourceVSphereHaVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereHaVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
+	_, cluster, vm, err := resourceVSphereHaVMOverrideObjects(d, meta)
 	if err != nil {
 		return err
 	}
@@ -190,12 +449,37 @@ func resourceVSphereHaVMOverrideDelete(d *schema.ResourceData, meta interface{})
 				},
 			},
 		},
+		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationRemove,
+					RemoveKey: vm.Reference(),
+				},
+			},
+		},
+	}
+
+	if d.Get("failure_domain_id").(string) != "" {
+		groupSpec, ruleSpec, err := resourceVSphereHaVMOverrideFailureDomainRemoveSpecs(cluster, vm)
+		if err != nil {
+			return err
+		}
+		if groupSpec != nil {
+			spec.GroupSpec = append(spec.GroupSpec, *groupSpec)
+		}
+		if ruleSpec != nil {
+			spec.RulesSpec = append(spec.RulesSpec, *ruleSpec)
+		}
 	}
 
 	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
 		return err
 	}
 
+	if err := resourceVSphereHaVMOverrideClearExtraConfig(d, vm); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereHaVMOverrideIDString(d))
 	return nil
 }
@@ -213,8 +497,13 @@ func resourceVSphereHaVMOverrideImport(d *schema.ResourceData, meta interface{})
 	if !ok {
 		return nil, errors.New("missing virtual_machine_path in input data")
 	}
+	// vcenter is optional in the import data. When it is omitted, the
+	// provider's default vcenter block (the first one declared) is used,
+	// since the cluster/VM haven't been located yet and so can't be walked
+	// across every configured endpoint the way compute_cluster_id can.
+	alias := data["vcenter"]
 
-	client, err := resourceVSphereHaVMOverrideClient(meta)
+	client, alias, err := resourceVSphereHaVMOverrideClientForAlias(meta, alias)
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +518,7 @@ func resourceVSphereHaVMOverrideImport(d *schema.ResourceData, meta interface{})
 		return nil, fmt.Errorf("cannot locate virtual machine %q: %s", vmPath, err)
 	}
 
-	id, err := resourceVSphereHaVMOverrideFlattenID(cluster, vm)
+	id, err := resourceVSphereHaVMOverrideFlattenID(alias, cluster, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot compute ID of imported resource: %s", err)
 	}
@@ -244,25 +533,42 @@ func resourceVSphereHaVMOverrideIDString(d structure.ResourceIDStringer) string
 }
 
 // resourceVSphereHaVMOverrideFlattenID makes an ID for the
-// vsphere_storage_drs_vm_config resource.
-func resourceVSphereHaVMOverrideFlattenID(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
+// vsphere_ha_vm_override resource. The ID is prefixed with the alias of the
+// vcenter block that owns cluster and vm, so that imports and refreshes
+// remain deterministic when a workload spans multiple vCenter endpoints.
+func resourceVSphereHaVMOverrideFlattenID(alias string, cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
 	clusterID := cluster.Reference().Value
 	props, err := virtualmachine.Properties(vm)
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
 	vmID := props.Config.Uuid
-	return strings.Join([]string{clusterID, vmID}, ":"), nil
+	return strings.Join([]string{alias, clusterID, vmID}, ":"), nil
 }
 
 // resourceVSphereHaVMOverrideParseID parses an ID for the
-// vsphere_storage_drs_vm_config and outputs its parts.
-func resourceVSphereHaVMOverrideParseID(id string) (string, string, error) {
-	parts := strings.SplitN(id, ":", 3)
-	if len(parts) < 2 {
-		return "", "", fmt.Errorf("bad ID %q", id)
+// vsphere_ha_vm_override resource and outputs its parts: the vcenter alias,
+// the cluster's managed object ID, and the virtual machine's UUID. The alias
+// defaults to the vcenter block's server address, which routinely contains a
+// colon itself (a port, or a scheme like https://vc/sdk), so the cluster ID
+// and UUID - both of which are colon-free - are peeled off from the right
+// instead of splitting the whole ID left-to-right.
+func resourceVSphereHaVMOverrideParseID(id string) (string, string, string, error) {
+	lastColon := strings.LastIndex(id, ":")
+	if lastColon < 0 {
+		return "", "", "", fmt.Errorf("bad ID %q", id)
 	}
-	return parts[0], parts[1], nil
+	vmID := id[lastColon+1:]
+
+	rest := id[:lastColon]
+	secondToLastColon := strings.LastIndex(rest, ":")
+	if secondToLastColon < 0 {
+		return "", "", "", fmt.Errorf("bad ID %q", id)
+	}
+	alias := rest[:secondToLastColon]
+	clusterID := rest[secondToLastColon+1:]
+
+	return alias, clusterID, vmID, nil
 }
 
 // resourceVSphereHaVMOverrideFindEntry attempts to locate an existing VM
@@ -288,15 +594,126 @@ func resourceVSphereHaVMOverrideFindEntry(
 	return nil, nil
 }
 
-// resourceVSphereHaVMOverrideObjects handles the fetching of the cluster and
-// virtual machine depending on what attributes are available:
-// * If the resource ID is available, the data is derived from the ID.
-// * If not, it's derived from the compute_cluster_id and virtual_machine_id
-// attributes.
+// resourceVSphereHaVMOverrideFindDasEntry attempts to locate an existing VM
+// config in a cluster's HA (DAS) configuration. It's used by the resource's
+// read functionality and tests. nil is returned if the entry cannot be found.
+func resourceVSphereHaVMOverrideFindDasEntry(
+	cluster *object.ClusterComputeResource,
+	vm *object.VirtualMachine,
+) (*types.ClusterDasVmConfigInfo, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+
+	for _, info := range props.ConfigurationEx.(*types.ClusterConfigInfoEx).DasVmConfig {
+		if info.Key == vm.Reference() {
+			log.Printf("[DEBUG] Found DAS config info for VM %q in cluster %q", vm.Name(), cluster.Name())
+			return &info, nil
+		}
+	}
+
+	log.Printf("[DEBUG] No DAS config info found for VM %q in cluster %q", vm.Name(), cluster.Name())
+	return nil, nil
+}
+
+// expandClusterDasVMConfigInfo reads certain ResourceData keys and returns a
+// ClusterDasVmConfigInfo, the per-VM HA override counterpart to
+// ClusterDrsVmConfigInfo, for use in a ClusterConfigSpecEx.
+func expandClusterDasVMConfigInfo(d *schema.ResourceData, vm *object.VirtualMachine) (*types.ClusterDasVmConfigInfo, error) {
+	restartPriorityTimeout := int32(d.Get("ha_vm_restart_priority_timeout").(int))
+	obj := &types.ClusterDasVmConfigInfo{
+		Key: vm.Reference(),
+		DasSettings: &types.ClusterDasVmSettings{
+			RestartPriority:        d.Get("ha_vm_restart_priority").(string),
+			RestartPriorityTimeout: &restartPriorityTimeout,
+			IsolationResponse:      d.Get("ha_host_isolation_response").(string),
+			VmToolsMonitoringSettings: &types.ClusterVmToolsMonitoringSettings{
+				Enabled:          structure.BoolPtr(true),
+				VmMonitoring:     d.Get("ha_vm_monitoring").(string),
+				ClusterSettings:  structure.BoolPtr(false),
+				FailureInterval:  int32(d.Get("ha_vm_failure_interval").(int)),
+				MinUpTime:        int32(d.Get("ha_vm_minimum_uptime").(int)),
+				MaxFailures:      int32(d.Get("ha_vm_maximum_failures").(int)),
+				MaxFailureWindow: int32(d.Get("ha_vm_maximum_failure_window").(int)),
+			},
+			VmComponentProtectionSettings: &types.ClusterVmComponentProtectionSettings{
+				VmStorageProtectionForAPD: d.Get("ha_datastore_apd_response").(string),
+				VmReactionOnAPDCleared:    d.Get("ha_datastore_apd_recovery_action").(string),
+				VmStorageProtectionForPDL: d.Get("ha_datastore_pdl_response").(string),
+			},
+		},
+	}
+
+	return obj, nil
+}
+
+// flattenClusterDasVMConfigInfo saves a ClusterDasVmConfigInfo into the
+// supplied ResourceData.
+func flattenClusterDasVMConfigInfo(d *schema.ResourceData, info *types.ClusterDasVmConfigInfo) error {
+	ds := info.DasSettings
+	if ds == nil {
+		return nil
+	}
+
+	if err := d.Set("ha_vm_restart_priority", ds.RestartPriority); err != nil {
+		return fmt.Errorf("error setting attribute \"ha_vm_restart_priority\": %s", err)
+	}
+	if ds.RestartPriorityTimeout != nil {
+		if err := d.Set("ha_vm_restart_priority_timeout", *ds.RestartPriorityTimeout); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_restart_priority_timeout\": %s", err)
+		}
+	}
+	if err := d.Set("ha_host_isolation_response", ds.IsolationResponse); err != nil {
+		return fmt.Errorf("error setting attribute \"ha_host_isolation_response\": %s", err)
+	}
+
+	if tm := ds.VmToolsMonitoringSettings; tm != nil {
+		if err := d.Set("ha_vm_monitoring", tm.VmMonitoring); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_monitoring\": %s", err)
+		}
+		if err := d.Set("ha_vm_failure_interval", tm.FailureInterval); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_failure_interval\": %s", err)
+		}
+		if err := d.Set("ha_vm_minimum_uptime", tm.MinUpTime); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_minimum_uptime\": %s", err)
+		}
+		if err := d.Set("ha_vm_maximum_failures", tm.MaxFailures); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_maximum_failures\": %s", err)
+		}
+		if err := d.Set("ha_vm_maximum_failure_window", tm.MaxFailureWindow); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_vm_maximum_failure_window\": %s", err)
+		}
+	}
+
+	if cp := ds.VmComponentProtectionSettings; cp != nil {
+		if err := d.Set("ha_datastore_apd_response", cp.VmStorageProtectionForAPD); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_datastore_apd_response\": %s", err)
+		}
+		if err := d.Set("ha_datastore_apd_recovery_action", cp.VmReactionOnAPDCleared); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_datastore_apd_recovery_action\": %s", err)
+		}
+		if err := d.Set("ha_datastore_pdl_response", cp.VmStorageProtectionForPDL); err != nil {
+			return fmt.Errorf("error setting attribute \"ha_datastore_pdl_response\": %s", err)
+		}
+	}
+
+	return nil
+}
+
+// resourceVSphereHaVMOverrideObjects handles the fetching of the vcenter
+// alias, cluster, and virtual machine depending on what attributes are
+// available:
+// * If the resource ID is available, the alias and the rest of the data are
+//   derived from the ID.
+// * If not, the alias comes from the vcenter attribute (if set, otherwise it
+//   is resolved by walking every configured vcenter block for the cluster),
+//   and the cluster/VM come from the compute_cluster_id and
+//   virtual_machine_id attributes.
 func resourceVSphereHaVMOverrideObjects(
 	d *schema.ResourceData,
 	meta interface{},
-) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
+) (string, *object.ClusterComputeResource, *object.VirtualMachine, error) {
 	if d.Id() != "" {
 		return resourceVSphereHaVMOverrideObjectsFromID(d, meta)
 	}
@@ -306,39 +723,42 @@ func resourceVSphereHaVMOverrideObjects(
 func resourceVSphereHaVMOverrideObjectsFromAttributes(
 	d *schema.ResourceData,
 	meta interface{},
-) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
-	return resourceVSphereHaVMOverrideFetchObjects(
-		meta,
-		d.Get("compute_cluster_id").(string),
-		d.Get("virtual_machine_id").(string),
-	)
+) (string, *object.ClusterComputeResource, *object.VirtualMachine, error) {
+	clusterID := d.Get("compute_cluster_id").(string)
+	client, alias, err := resourceVSphereHaVMOverrideClientForCluster(meta, d.Get("vcenter").(string), clusterID)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cluster, vm, err := resourceVSphereHaVMOverrideFetchObjects(client, clusterID, d.Get("virtual_machine_id").(string))
+	return alias, cluster, vm, err
 }
 
 func resourceVSphereHaVMOverrideObjectsFromID(
 	d structure.ResourceIDStringer,
 	meta interface{},
-) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
+) (string, *object.ClusterComputeResource, *object.VirtualMachine, error) {
 	// Note that this function uses structure.ResourceIDStringer to satisfy
 	// interfacer. Adding exceptions in the comments does not seem to work.
 	// Change this back to ResourceData if it's needed in the future.
-	clusterID, vmID, err := resourceVSphereHaVMOverrideParseID(d.Id())
+	alias, clusterID, vmID, err := resourceVSphereHaVMOverrideParseID(d.Id())
 	if err != nil {
-		return nil, nil, err
+		return "", nil, nil, err
 	}
 
-	return resourceVSphereHaVMOverrideFetchObjects(meta, clusterID, vmID)
+	client, alias, err := resourceVSphereHaVMOverrideClientForAlias(meta, alias)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	cluster, vm, err := resourceVSphereHaVMOverrideFetchObjects(client, clusterID, vmID)
+	return alias, cluster, vm, err
 }
 
 func resourceVSphereHaVMOverrideFetchObjects(
-	meta interface{},
+	client *govmomi.Client,
 	clusterID string,
 	vmID string,
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
-	client, err := resourceVSphereHaVMOverrideClient(meta)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	cluster, err := clustercomputeresource.FromID(client, clusterID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
@@ -352,10 +772,425 @@ func resourceVSphereHaVMOverrideFetchObjects(
 	return cluster, vm, nil
 }
 
-func resourceVSphereHaVMOverrideClient(meta interface{}) (*govmomi.Client, error) {
-	client := meta.(*VSphereClient).vimClient
+// resourceVSphereHaVMOverrideClientForAlias resolves the connection
+// registered for alias (or the provider's default connection, if alias is
+// empty), validating that it is a connection to a vCenter instance.
+func resourceVSphereHaVMOverrideClientForAlias(meta interface{}, alias string) (*govmomi.Client, string, error) {
+	vsc := meta.(*VSphereClient)
+	client, err := vsc.clientForAlias(alias)
+	if err != nil {
+		return nil, "", err
+	}
+	if alias == "" {
+		alias = vsc.defaultAlias
+	}
+	if err := viapi.ValidateVirtualCenter(client); err != nil {
+		return nil, "", err
+	}
+	return client, alias, nil
+}
+
+// resourceVSphereHaVMOverrideClientForCluster resolves the connection that
+// owns clusterID. If alias is set, it is used directly; otherwise every
+// configured vcenter block is walked, and an error is returned unless
+// exactly one of them knows about clusterID.
+func resourceVSphereHaVMOverrideClientForCluster(meta interface{}, alias string, clusterID string) (*govmomi.Client, string, error) {
+	if alias != "" {
+		return resourceVSphereHaVMOverrideClientForAlias(meta, alias)
+	}
+
+	vsc := meta.(*VSphereClient)
+	resolvedAlias, client, err := vsc.clientForCluster(clusterID)
+	if err != nil {
+		return nil, "", err
+	}
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
+		return nil, "", err
+	}
+	return client, resolvedAlias, nil
+}
+
+// resourceVSphereHaVMOverridePreflight runs the checks in the preflight
+// package against cluster and vm before a Reconfigure call is submitted.
+// Diagnostics at preflight.SeverityError are collected into a single error;
+// diagnostics at preflight.SeverityWarning are logged and otherwise do not
+// block the operation. Set skip_preflight to bypass this entirely.
+func resourceVSphereHaVMOverridePreflight(d *schema.ResourceData, cluster *object.ClusterComputeResource, vm *object.VirtualMachine) error {
+	if d.Get("skip_preflight").(bool) {
+		log.Printf("[DEBUG] %s: skip_preflight is set, bypassing preflight checks", resourceVSphereHaVMOverrideIDString(d))
+		return nil
+	}
+
+	var checks []func() (preflight.Diagnostic, error)
+	if resourceVSphereHaVMOverrideHAFieldsConfigured(d) {
+		checks = append(checks, func() (preflight.Diagnostic, error) { return preflight.ClusterHasHAEnabled(cluster) })
+	}
+	if resourceVSphereHaVMOverrideDRSFieldsConfigured(d) {
+		checks = append(checks, func() (preflight.Diagnostic, error) { return preflight.ClusterHasDRSEnabled(cluster) })
+	}
+	checks = append(checks,
+		func() (preflight.Diagnostic, error) { return preflight.VMIsClusterMember(cluster, vm) },
+		func() (preflight.Diagnostic, error) { return preflight.UserHasEditClusterPrivilege(cluster) },
+		func() (preflight.Diagnostic, error) { return preflight.APDPDLFieldsSupported(cluster) },
+	)
+
+	var errs []string
+	for _, check := range checks {
+		diag, err := check()
+		if err != nil {
+			return fmt.Errorf("error running preflight checks: %s", err)
+		}
+		if diag.Summary == "" {
+			continue
+		}
+		switch diag.Severity {
+		case preflight.SeverityError:
+			errs = append(errs, fmt.Sprintf("%s (%s)", diag.Summary, diag.Remediation))
+		default:
+			log.Printf("[WARN] %s: %s (%s)", resourceVSphereHaVMOverrideIDString(d), diag.Summary, diag.Remediation)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("preflight checks failed:\n%s\nset skip_preflight to true to bypass these checks", strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// drsFieldConfigKeys and haFieldConfigKeys list the attributes that gate
+// whether resourceVSphereHaVMOverridePreflight requires DRS/HA to be enabled
+// on the target cluster. They are tested with d.GetOkExists. Every attribute
+// in these groups carries a schema Default, so d.GetOkExists reports each one
+// as present whether or not the configuration sets it: an attribute left at
+// its default is not told apart from one set explicitly.
+var drsFieldConfigKeys = []string{
+	"drs_enabled",
+	"drs_automation_level",
+}
+
+var haFieldConfigKeys = []string{
+	"ha_vm_restart_priority",
+	"ha_vm_restart_priority_timeout",
+	"ha_host_isolation_response",
+	"ha_vm_monitoring",
+	"ha_vm_failure_interval",
+	"ha_vm_minimum_uptime",
+	"ha_vm_maximum_failures",
+	"ha_vm_maximum_failure_window",
+	"ha_datastore_apd_response",
+	"ha_datastore_apd_recovery_action",
+	"ha_datastore_pdl_response",
+}
+
+// resourceVSphereHaVMOverrideDRSFieldsConfigured returns true if any of the
+// DRS-specific attributes in this resource exists in d. Because each of them
+// has a schema Default, this is true for every configuration.
+func resourceVSphereHaVMOverrideDRSFieldsConfigured(d *schema.ResourceData) bool {
+	for _, key := range drsFieldConfigKeys {
+		if _, ok := d.GetOkExists(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceVSphereHaVMOverrideHAFieldsConfigured returns true if any of the
+// HA-specific attributes in this resource exists in d. Because each of them
+// has a schema Default, this is true for every configuration.
+func resourceVSphereHaVMOverrideHAFieldsConfigured(d *schema.ResourceData) bool {
+	for _, key := range haFieldConfigKeys {
+		if _, ok := d.GetOkExists(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandFailureDomainPlacement builds the VM group and affinity rule specs
+// needed to place vm into the failure domain referenced by failure_domain_id.
+// ok is false if failure_domain_id is unset, in which case groupSpec and
+// ruleSpec are nil and should be ignored. Unlike the DRS/DAS VmConfig arrays,
+// cluster groups are keyed by name and rules are keyed by an integer Key, so
+// re-submitting Add against an entry that already exists (as happens on every
+// update after the initial create) faults or duplicates the rule; the
+// returned specs use Edit against the existing group/rule when one is
+// already present.
+func expandFailureDomainPlacement(d *schema.ResourceData, cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (groupSpec *types.ClusterGroupSpec, ruleSpec *types.ClusterRuleSpec, ok bool, err error) {
+	failureDomainID := d.Get("failure_domain_id").(string)
+	if failureDomainID == "" {
+		return nil, nil, false, nil
+	}
+
+	_, _, hostGroupName, err := resourceVSphereComputeClusterFailureDomainParseID(failureDomainID)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("invalid failure_domain_id: %s", err)
+	}
+
+	props, err := virtualmachine.Properties(vm)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error getting properties of virtual machine: %s", err)
+	}
+	vmGroupName := resourceVSphereHaVMOverrideFailureDomainVMGroupName(props.Config.Uuid)
+	ruleName := resourceVSphereHaVMOverrideFailureDomainRuleName(props.Config.Uuid)
+
+	groupSpec, err = resourceVSphereHaVMOverrideFailureDomainGroupSpec(cluster, vm, vmGroupName)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	ruleSpec, err = resourceVSphereHaVMOverrideFailureDomainRuleSpec(cluster, vmGroupName, hostGroupName, ruleName)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return groupSpec, ruleSpec, true, nil
+}
+
+// resourceVSphereHaVMOverrideFailureDomainGroupSpec builds the ClusterGroupSpec
+// for the VM group named name, using Edit if a group with that name already
+// exists in cluster's configuration and Add otherwise.
+func resourceVSphereHaVMOverrideFailureDomainGroupSpec(cluster *object.ClusterComputeResource, vm *object.VirtualMachine, name string) (*types.ClusterGroupSpec, error) {
+	exists, err := resourceVSphereHaVMOverrideGroupExists(cluster, name)
+	if err != nil {
+		return nil, err
+	}
+
+	op := types.ArrayUpdateOperationAdd
+	if exists {
+		op = types.ArrayUpdateOperationEdit
+	}
+
+	return &types.ClusterGroupSpec{
+		ArrayUpdateSpec: types.ArrayUpdateSpec{
+			Operation: op,
+		},
+		Info: &types.ClusterVmGroup{
+			ClusterGroupInfo: types.ClusterGroupInfo{
+				Name: name,
+			},
+			Vm: []types.ManagedObjectReference{vm.Reference()},
+		},
+	}, nil
+}
+
+// resourceVSphereHaVMOverrideGroupExists returns true if cluster's
+// configuration already has a group (of any kind) named name.
+func resourceVSphereHaVMOverrideGroupExists(cluster *object.ClusterComputeResource, name string) (bool, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return false, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+	for _, g := range props.ConfigurationEx.(*types.ClusterConfigInfoEx).Group {
+		if g.GetClusterGroupInfo().Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resourceVSphereHaVMOverrideFailureDomainRuleSpec builds the ClusterRuleSpec
+// for the should-run-on affinity rule named ruleName, using Edit against the
+// rule's existing Key if a rule with that name already exists in cluster's
+// configuration and Add otherwise.
+func resourceVSphereHaVMOverrideFailureDomainRuleSpec(cluster *object.ClusterComputeResource, vmGroupName, hostGroupName, ruleName string) (*types.ClusterRuleSpec, error) {
+	key, exists, err := resourceVSphereHaVMOverrideFindRuleKey(cluster, ruleName)
+	if err != nil {
 		return nil, err
 	}
-	return client, nil
-}
\ No newline at end of file
+
+	info := &types.ClusterVmHostRuleInfo{
+		ClusterRuleInfo: types.ClusterRuleInfo{
+			Name:    ruleName,
+			Enabled: structure.BoolPtr(true),
+		},
+		VmGroupName:         vmGroupName,
+		AffineHostGroupName: hostGroupName,
+	}
+
+	op := types.ArrayUpdateOperationAdd
+	if exists {
+		op = types.ArrayUpdateOperationEdit
+		info.ClusterRuleInfo.Key = key
+	}
+
+	return &types.ClusterRuleSpec{
+		ArrayUpdateSpec: types.ArrayUpdateSpec{
+			Operation: op,
+		},
+		Info: info,
+	}, nil
+}
+
+// resourceVSphereHaVMOverrideFindRuleKey returns the integer Key of the rule
+// named name in cluster's configuration, if one exists.
+func resourceVSphereHaVMOverrideFindRuleKey(cluster *object.ClusterComputeResource, name string) (int32, bool, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return 0, false, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+	for _, r := range props.ConfigurationEx.(*types.ClusterConfigInfoEx).Rule {
+		info := r.GetClusterRuleInfo()
+		if info.Name == name {
+			return info.Key, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// resourceVSphereHaVMOverrideFailureDomainRemoveSpecs builds the removal
+// specs for the VM group and affinity rule that this resource auto-creates
+// when failure_domain_id is set. The shared host group that failure_domain_id
+// refers to is left untouched; only the VM group and rule scoped to this
+// specific VM are torn down. ruleSpec is nil if no matching rule is found
+// (for example, because the cluster configuration was already cleaned up out
+// of band).
+func resourceVSphereHaVMOverrideFailureDomainRemoveSpecs(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (groupSpec *types.ClusterGroupSpec, ruleSpec *types.ClusterRuleSpec, err error) {
+	props, err := virtualmachine.Properties(vm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting properties of virtual machine: %s", err)
+	}
+	vmGroupName := resourceVSphereHaVMOverrideFailureDomainVMGroupName(props.Config.Uuid)
+	ruleName := resourceVSphereHaVMOverrideFailureDomainRuleName(props.Config.Uuid)
+
+	groupSpec = &types.ClusterGroupSpec{
+		ArrayUpdateSpec: types.ArrayUpdateSpec{
+			Operation: types.ArrayUpdateOperationRemove,
+			RemoveKey: vmGroupName,
+		},
+	}
+
+	clusterProps, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+	for _, r := range clusterProps.ConfigurationEx.(*types.ClusterConfigInfoEx).Rule {
+		info := r.GetClusterRuleInfo()
+		if info.Name != ruleName {
+			continue
+		}
+		ruleSpec = &types.ClusterRuleSpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationRemove,
+				RemoveKey: info.Key,
+			},
+		}
+		break
+	}
+
+	return groupSpec, ruleSpec, nil
+}
+
+// resourceVSphereHaVMOverrideFailureDomainPlacementExists returns true if the
+// VM group and should-run-on rule that this resource creates for vmUUID when
+// failure_domain_id is set are both still present in cluster's configuration.
+func resourceVSphereHaVMOverrideFailureDomainPlacementExists(cluster *object.ClusterComputeResource, vmUUID string) (bool, error) {
+	groupExists, err := resourceVSphereHaVMOverrideGroupExists(cluster, resourceVSphereHaVMOverrideFailureDomainVMGroupName(vmUUID))
+	if err != nil {
+		return false, err
+	}
+	if !groupExists {
+		return false, nil
+	}
+
+	_, ruleExists, err := resourceVSphereHaVMOverrideFindRuleKey(cluster, resourceVSphereHaVMOverrideFailureDomainRuleName(vmUUID))
+	if err != nil {
+		return false, err
+	}
+	return ruleExists, nil
+}
+
+// resourceVSphereHaVMOverrideFailureDomainVMGroupName returns the
+// deterministic name of the VM group that this resource creates for vmUUID
+// when failure_domain_id is set.
+func resourceVSphereHaVMOverrideFailureDomainVMGroupName(vmUUID string) string {
+	return fmt.Sprintf("tf-ha-override-vm-group-%s", vmUUID)
+}
+
+// resourceVSphereHaVMOverrideFailureDomainRuleName returns the deterministic
+// name of the affinity rule that this resource creates for vmUUID when
+// failure_domain_id is set.
+func resourceVSphereHaVMOverrideFailureDomainRuleName(vmUUID string) string {
+	return fmt.Sprintf("tf-ha-override-rule-%s", vmUUID)
+}
+
+// resourceVSphereHaVMOverrideApplyExtraConfig submits the extra_config
+// attribute to vm via VirtualMachine.Reconfigure. Keys that were present in
+// the prior extra_config value but are absent from the new one are submitted
+// with an empty value, since vSphere unsets an ExtraConfig entry only when
+// given an explicit empty value, never by omission.
+func resourceVSphereHaVMOverrideApplyExtraConfig(d *schema.ResourceData, vm *object.VirtualMachine) error {
+	oldRaw, newRaw := d.GetChange("extra_config")
+	oldConfig := oldRaw.(map[string]interface{})
+	newConfig := newRaw.(map[string]interface{})
+
+	var opts []types.BaseOptionValue
+	for k, v := range newConfig {
+		opts = append(opts, &types.OptionValue{Key: k, Value: v.(string)})
+	}
+	for k := range oldConfig {
+		if _, ok := newConfig[k]; !ok {
+			opts = append(opts, &types.OptionValue{Key: k, Value: ""})
+		}
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+
+	if err := virtualmachine.Reconfigure(vm, types.VirtualMachineConfigSpec{ExtraConfig: opts}); err != nil {
+		return fmt.Errorf("error applying attribute \"extra_config\": %s", err)
+	}
+	return nil
+}
+
+// resourceVSphereHaVMOverrideClearExtraConfig unsets every key currently
+// tracked in the extra_config attribute, submitting an empty-value
+// OptionValue for each, as required by vSphere's ExtraConfig removal
+// semantics.
+func resourceVSphereHaVMOverrideClearExtraConfig(d *schema.ResourceData, vm *object.VirtualMachine) error {
+	config := d.Get("extra_config").(map[string]interface{})
+	if len(config) == 0 {
+		return nil
+	}
+
+	opts := make([]types.BaseOptionValue, 0, len(config))
+	for k := range config {
+		opts = append(opts, &types.OptionValue{Key: k, Value: ""})
+	}
+
+	if err := virtualmachine.Reconfigure(vm, types.VirtualMachineConfigSpec{ExtraConfig: opts}); err != nil {
+		return fmt.Errorf("error clearing attribute \"extra_config\": %s", err)
+	}
+	return nil
+}
+
+// resourceVSphereHaVMOverrideFlattenExtraConfig saves the current values of
+// the keys already tracked in the extra_config attribute. Only keys that
+// Terraform already manages are considered; this deliberately ignores
+// vCenter-added entries (guestinfo.*, tools.*, and the like) that show up in
+// a virtual machine's ExtraConfig without ever having been set by this
+// resource.
+func resourceVSphereHaVMOverrideFlattenExtraConfig(d *schema.ResourceData, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+	managed := d.Get("extra_config").(map[string]interface{})
+	if len(managed) == 0 {
+		return nil
+	}
+
+	current := make(map[string]string, len(props.Config.ExtraConfig))
+	for _, bov := range props.Config.ExtraConfig {
+		ov := bov.GetOptionValue()
+		if s, ok := ov.Value.(string); ok {
+			current[ov.Key] = s
+		}
+	}
+
+	out := make(map[string]interface{}, len(managed))
+	for k := range managed {
+		if v, ok := current[k]; ok {
+			out[k] = v
+		}
+	}
+	if err := d.Set("extra_config", out); err != nil {
+		return fmt.Errorf("error setting attribute \"extra_config\": %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/terraform-providers/terraform-provider-vsphere/vsphere/internal/preflight"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const resourceVSphereHaVMOverrideSetName = "vsphere_ha_vm_override_set"
+
+// resourceVSphereHaVMOverrideSet applies a single set of DRS/HA overrides to
+// every virtual machine matched by one or more inventory path globs, in a
+// single Reconfigure call. It exists alongside vsphere_ha_vm_override to
+// avoid the N-resource, N-round-trip pattern of applying identical overrides
+// to dozens of virtual machines in a cluster.
+func resourceVSphereHaVMOverrideSet() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereHaVMOverrideSetCreate,
+		Read:   resourceVSphereHaVMOverrideSetRead,
+		Update: resourceVSphereHaVMOverrideSetUpdate,
+		Delete: resourceVSphereHaVMOverrideSetDelete,
+
+		Schema: mergeSchema(map[string]*schema.Schema{
+			"compute_cluster_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The managed object ID of the cluster.",
+			},
+			"vcenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The alias of the vcenter provider block to use for this resource's cluster, as set in that block's alias attribute. If unset, the provider locates compute_cluster_id by walking every configured vcenter block.",
+			},
+			"virtual_machine_paths": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A list of inventory path globs (for example /DC/vm/prod/web-*) that determine the virtual machines this set of overrides applies to. Re-evaluated on every apply, so matching membership can grow or shrink without changing the resource's ID.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"matched_virtual_machine_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The managed object IDs of the virtual machines that currently match virtual_machine_paths and have this set of overrides applied.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}, haVMOverrideFieldSchema()),
+	}
+}
+
+func resourceVSphereHaVMOverrideSetCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereHaVMOverrideSetIDString(d))
+
+	alias, _, cluster, err := resourceVSphereHaVMOverrideSetCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	vms, err := resourceVSphereHaVMOverrideSetMatches(d, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := resourceVSphereHaVMOverrideSetPreflight(d, cluster, vms); err != nil {
+		return err
+	}
+
+	if err := resourceVSphereHaVMOverrideSetApply(d, cluster, nil, vms); err != nil {
+		return err
+	}
+
+	d.SetId(strings.Join([]string{alias, cluster.Reference().Value}, ":"))
+
+	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereHaVMOverrideSetIDString(d))
+	return resourceVSphereHaVMOverrideSetRead(d, meta)
+}
+
+func resourceVSphereHaVMOverrideSetRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereHaVMOverrideSetIDString(d))
+
+	_, _, cluster, err := resourceVSphereHaVMOverrideSetCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	vms, err := resourceVSphereHaVMOverrideSetMatches(d, cluster)
+	if err != nil {
+		return err
+	}
+
+	applied, err := resourceVSphereHaVMOverrideSetAppliedVMIDs(cluster)
+	if err != nil {
+		return err
+	}
+
+	// Only report a VM as matched if it is both still matched by the globs
+	// and still carries the DRS/DAS overrides this resource applies. A VM
+	// that still matches but whose override was removed out of band (or one
+	// that has dropped out of the globs since the last apply) is dropped
+	// from the tracked set here, so the next Update sees it as needing an
+	// Add/Remove rather than treating it as already reconciled.
+	managed := make([]*object.VirtualMachine, 0, len(vms))
+	for _, vm := range vms {
+		if applied[vm.Reference().Value] {
+			managed = append(managed, vm)
+		}
+	}
+
+	if err := d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
+		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+	}
+	if err := d.Set("matched_virtual_machine_ids", resourceVSphereHaVMOverrideSetVMIDs(managed)); err != nil {
+		return fmt.Errorf("error setting attribute \"matched_virtual_machine_ids\": %s", err)
+	}
+
+	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereHaVMOverrideSetIDString(d))
+	return nil
+}
+
+func resourceVSphereHaVMOverrideSetUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereHaVMOverrideSetIDString(d))
+
+	_, client, cluster, err := resourceVSphereHaVMOverrideSetCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	vms, err := resourceVSphereHaVMOverrideSetMatches(d, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := resourceVSphereHaVMOverrideSetPreflight(d, cluster, vms); err != nil {
+		return err
+	}
+
+	oldRaw, _ := d.GetChange("matched_virtual_machine_ids")
+	var stale []*object.VirtualMachine
+	newIDs := resourceVSphereHaVMOverrideSetVMIDs(vms)
+	for _, raw := range oldRaw.(*schema.Set).List() {
+		id := raw.(string)
+		if !containsString(newIDs, id) {
+			// id is the VM's managed object ID (matched_virtual_machine_ids is
+			// populated from vm.Reference().Value, not an instance UUID), so the
+			// reference can be built directly without a property collector round
+			// trip; ArrayUpdateOperationRemove only needs the MoRef, not the VM's
+			// current properties.
+			stale = append(stale, object.NewVirtualMachine(client.Client, types.ManagedObjectReference{Type: "VirtualMachine", Value: id}))
+		}
+	}
+
+	if err := resourceVSphereHaVMOverrideSetApply(d, cluster, stale, vms); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereHaVMOverrideSetIDString(d))
+	return resourceVSphereHaVMOverrideSetRead(d, meta)
+}
+
+func resourceVSphereHaVMOverrideSetDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereHaVMOverrideSetIDString(d))
+
+	_, _, cluster, err := resourceVSphereHaVMOverrideSetCluster(d, meta)
+	if err != nil {
+		return err
+	}
+
+	vms, err := resourceVSphereHaVMOverrideSetMatches(d, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := resourceVSphereHaVMOverrideSetApply(d, cluster, vms, nil); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereHaVMOverrideSetIDString(d))
+	return nil
+}
+
+// resourceVSphereHaVMOverrideSetIDString prints a friendly string for the
+// vsphere_ha_vm_override_set resource.
+func resourceVSphereHaVMOverrideSetIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereHaVMOverrideSetName)
+}
+
+// resourceVSphereHaVMOverrideSetCluster resolves the vcenter alias, client,
+// and cluster that this resource targets, either from
+// compute_cluster_id/vcenter (for new or not-yet-imported resources) or from
+// the resource ID.
+func resourceVSphereHaVMOverrideSetCluster(d *schema.ResourceData, meta interface{}) (string, *govmomi.Client, *object.ClusterComputeResource, error) {
+	if d.Id() != "" {
+		// The alias defaults to the vcenter block's server address, which
+		// routinely contains a colon itself (a port, or a scheme like
+		// https://vc/sdk), so the cluster ID - which is colon-free - is
+		// peeled off from the right instead of splitting the whole ID
+		// left-to-right.
+		lastColon := strings.LastIndex(d.Id(), ":")
+		if lastColon < 0 {
+			return "", nil, nil, fmt.Errorf("bad ID %q", d.Id())
+		}
+		alias, clusterID := d.Id()[:lastColon], d.Id()[lastColon+1:]
+
+		client, alias, err := resourceVSphereHaVMOverrideClientForAlias(meta, alias)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		cluster, err := clustercomputeresource.FromID(client, clusterID)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
+		}
+		return alias, client, cluster, nil
+	}
+
+	clusterID := d.Get("compute_cluster_id").(string)
+	client, alias, err := resourceVSphereHaVMOverrideClientForCluster(meta, d.Get("vcenter").(string), clusterID)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
+	}
+	return alias, client, cluster, nil
+}
+
+// resourceVSphereHaVMOverrideSetMatches resolves virtual_machine_paths
+// against the inventory, returning the de-duplicated union of every
+// virtual machine matched by any of the path globs.
+func resourceVSphereHaVMOverrideSetMatches(d *schema.ResourceData, cluster *object.ClusterComputeResource) ([]*object.VirtualMachine, error) {
+	finder := find.NewFinder(cluster.Client(), false)
+
+	seen := make(map[string]*object.VirtualMachine)
+	for _, raw := range d.Get("virtual_machine_paths").([]interface{}) {
+		path := raw.(string)
+		matches, err := finder.VirtualMachineList(context.Background(), path)
+		if err != nil {
+			if _, ok := err.(*find.NotFoundError); ok {
+				log.Printf("[DEBUG] %s: no virtual machines matched path %q", resourceVSphereHaVMOverrideSetIDString(d), path)
+				continue
+			}
+			return nil, fmt.Errorf("error resolving virtual machine path %q: %s", path, err)
+		}
+		for _, vm := range matches {
+			seen[vm.Reference().Value] = vm
+		}
+	}
+
+	vms := make([]*object.VirtualMachine, 0, len(seen))
+	for _, vm := range seen {
+		vms = append(vms, vm)
+	}
+	sort.Slice(vms, func(i, j int) bool { return vms[i].Reference().Value < vms[j].Reference().Value })
+	return vms, nil
+}
+
+// resourceVSphereHaVMOverrideSetVMIDs returns the sorted list of managed
+// object IDs for vms.
+func resourceVSphereHaVMOverrideSetVMIDs(vms []*object.VirtualMachine) []string {
+	ids := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		ids = append(ids, vm.Reference().Value)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// resourceVSphereHaVMOverrideSetAppliedVMIDs returns the set of managed
+// object IDs that currently have both a DrsVmConfig and a DasVmConfig entry
+// in cluster's configuration - i.e. the VMs this resource's overrides are
+// actually applied to, as opposed to the VMs that merely match the resource's
+// virtual_machine_paths globs right now.
+func resourceVSphereHaVMOverrideSetAppliedVMIDs(cluster *object.ClusterComputeResource) (map[string]bool, error) {
+	props, err := clustercomputeresource.Properties(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
+	}
+	config := props.ConfigurationEx.(*types.ClusterConfigInfoEx)
+
+	drs := make(map[string]bool, len(config.DrsVmConfig))
+	for _, info := range config.DrsVmConfig {
+		drs[info.Key.Value] = true
+	}
+
+	applied := make(map[string]bool, len(config.DasVmConfig))
+	for _, info := range config.DasVmConfig {
+		if drs[info.Key.Value] {
+			applied[info.Key.Value] = true
+		}
+	}
+	return applied, nil
+}
+
+// resourceVSphereHaVMOverrideSetPreflight runs the cluster-wide preflight
+// checks once, plus a cluster-membership check for every matched virtual
+// machine.
+func resourceVSphereHaVMOverrideSetPreflight(d *schema.ResourceData, cluster *object.ClusterComputeResource, vms []*object.VirtualMachine) error {
+	if d.Get("skip_preflight").(bool) {
+		log.Printf("[DEBUG] %s: skip_preflight is set, bypassing preflight checks", resourceVSphereHaVMOverrideSetIDString(d))
+		return nil
+	}
+
+	clusterChecks := []func() (preflight.Diagnostic, error){
+		func() (preflight.Diagnostic, error) { return preflight.ClusterHasHAEnabled(cluster) },
+		func() (preflight.Diagnostic, error) { return preflight.ClusterHasDRSEnabled(cluster) },
+		func() (preflight.Diagnostic, error) { return preflight.UserHasEditClusterPrivilege(cluster) },
+		func() (preflight.Diagnostic, error) { return preflight.APDPDLFieldsSupported(cluster) },
+	}
+
+	var errs []string
+	for _, check := range clusterChecks {
+		diag, err := check()
+		if err != nil {
+			return fmt.Errorf("error running preflight checks: %s", err)
+		}
+		if diag.Summary == "" {
+			continue
+		}
+		if diag.Severity == preflight.SeverityError {
+			errs = append(errs, fmt.Sprintf("%s (%s)", diag.Summary, diag.Remediation))
+		} else {
+			log.Printf("[WARN] %s: %s (%s)", resourceVSphereHaVMOverrideSetIDString(d), diag.Summary, diag.Remediation)
+		}
+	}
+
+	for _, vm := range vms {
+		diag, err := preflight.VMIsClusterMember(cluster, vm)
+		if err != nil {
+			return fmt.Errorf("error running preflight checks: %s", err)
+		}
+		if diag.Summary != "" && diag.Severity == preflight.SeverityError {
+			errs = append(errs, fmt.Sprintf("%s (%s)", diag.Summary, diag.Remediation))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("preflight checks failed:\n%s\nset skip_preflight to true to bypass these checks", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// resourceVSphereHaVMOverrideSetApply submits a single ClusterConfigSpecEx
+// with one ArrayUpdateOperationRemove entry per VM in remove and one
+// ArrayUpdateOperationAdd entry per VM in add, for both the DRS and the DAS
+// configuration, in a single Reconfigure call.
+func resourceVSphereHaVMOverrideSetApply(d *schema.ResourceData, cluster *object.ClusterComputeResource, remove, add []*object.VirtualMachine) error {
+	spec := &types.ClusterConfigSpecEx{}
+
+	for _, vm := range remove {
+		spec.DrsVmConfigSpec = append(spec.DrsVmConfigSpec, types.ClusterDrsVmConfigSpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationRemove,
+				RemoveKey: vm.Reference(),
+			},
+		})
+		spec.DasVmConfigSpec = append(spec.DasVmConfigSpec, types.ClusterDasVmConfigSpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationRemove,
+				RemoveKey: vm.Reference(),
+			},
+		})
+	}
+
+	for _, vm := range add {
+		drsInfo, err := expandClusterDrsVMConfigInfo(d, vm)
+		if err != nil {
+			return err
+		}
+		dasInfo, err := expandClusterDasVMConfigInfo(d, vm)
+		if err != nil {
+			return err
+		}
+		spec.DrsVmConfigSpec = append(spec.DrsVmConfigSpec, types.ClusterDrsVmConfigSpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				// NOTE: Add here replaces any existing entry for this VM
+				// wholesale, the same semantics vsphere_ha_vm_override relies on.
+				Operation: types.ArrayUpdateOperationAdd,
+			},
+			Info: drsInfo,
+		})
+		spec.DasVmConfigSpec = append(spec.DasVmConfigSpec, types.ClusterDasVmConfigSpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationAdd,
+			},
+			Info: *dasInfo,
+		})
+	}
+
+	if len(spec.DrsVmConfigSpec) == 0 {
+		return nil
+	}
+
+	return clustercomputeresource.Reconfigure(cluster, spec)
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}